@@ -0,0 +1,503 @@
+package mtg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	cardsBucket = []byte("cards")
+	metaBucket  = []byte("meta")
+
+	lastSyncedKey   = []byte("lastSynced")
+	sourceKey       = []byte("source")
+	etagKey         = []byte("etag")
+	lastModifiedKey = []byte("lastModified")
+)
+
+// BulkStore is an offline Backend. It ingests a full MTGJSON AllPrintings
+// dump (or a magicthegathering.io paginated "cards" dump) into an on-disk
+// BoltDB index once, then answers Query calls locally with no network
+// access. Use NewBulkStore to open one, Sync or Ingest to populate it, and
+// Query to get a Query that runs against the local index.
+type BulkStore struct {
+	db *bolt.DB
+
+	scanMu    sync.Mutex
+	scanCache map[string][]*Card
+}
+
+// NewBulkStore opens (creating if necessary) a BoltDB-backed index at path.
+// Call Ingest or Sync to populate it before querying.
+func NewBulkStore(path string) (*BulkStore, error) {
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(cardsBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(metaBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BulkStore{db: db}, nil
+}
+
+// Close closes the underlying BoltDB file.
+func (bs *BulkStore) Close() error {
+	return bs.db.Close()
+}
+
+// Query returns a Query that runs against this BulkStore's local index
+// instead of the network.
+func (bs *BulkStore) Query() Query {
+	return query{backend: bs, ctx: context.Background(), values: make(map[string]string)}
+}
+
+// bulkCards is the shape of a magicthegathering.io paginated dump, and also
+// matches the "cards" key nested under each set in an MTGJSON AllPrintings
+// dump.
+type bulkCards struct {
+	Cards []json.RawMessage `json:"cards"`
+}
+
+// bulkPrintings is the shape of an MTGJSON AllPrintings/AllSets dump: a map
+// of set code to set data, each carrying its own card list.
+type bulkPrintings struct {
+	Data map[string]bulkCards `json:"data"`
+}
+
+// Ingest reads a full bulk-data JSON dump from r and (re)builds the index
+// from it. It accepts either an MTGJSON AllPrintings/AllSets dump (a "data"
+// object keyed by set code) or a flat magicthegathering.io "cards" dump.
+func (bs *BulkStore) Ingest(r io.Reader) error {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	var printings bulkPrintings
+	if err := json.Unmarshal(raw, &printings); err != nil {
+		return fmt.Errorf("mtg: decoding bulk data: %w", err)
+	}
+
+	var records []json.RawMessage
+	if len(printings.Data) > 0 {
+		for _, set := range printings.Data {
+			records = append(records, set.Cards...)
+		}
+	} else {
+		var flat bulkCards
+		if err := json.Unmarshal(raw, &flat); err != nil {
+			return fmt.Errorf("mtg: decoding bulk data: %w", err)
+		}
+		records = flat.Cards
+	}
+
+	if err := bs.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(cardsBucket); err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+		bucket, err := tx.CreateBucket(cardsBucket)
+		if err != nil {
+			return err
+		}
+
+		for i, rec := range records {
+			var fields map[string]interface{}
+			if err := json.Unmarshal(rec, &fields); err != nil {
+				return err
+			}
+
+			key, ok := fields["id"].(string)
+			if !ok || key == "" {
+				key = strconv.Itoa(i)
+			}
+			if err := bucket.Put([]byte(key), rec); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	bs.scanMu.Lock()
+	bs.scanCache = nil
+	bs.scanMu.Unlock()
+	return nil
+}
+
+// Sync downloads source (an MTGJSON/MTGAPI bulk-data URL) and re-ingests it,
+// caching the raw response under the OS cache dir keyed by source. It sends
+// the ETag/Last-Modified from the previous Sync of the same source as
+// If-None-Match/If-Modified-Since, so a 304 response (the remote copy is
+// unchanged) skips both the download and the re-ingest entirely. ctx
+// governs the request.
+func (bs *BulkStore) Sync(ctx context.Context, source string) error {
+	cachePath, err := bulkCachePath(source)
+	if err != nil {
+		return err
+	}
+
+	var lastSource, etag, lastModified string
+	bs.db.View(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(metaBucket)
+		lastSource = string(meta.Get(sourceKey))
+		etag = string(meta.Get(etagKey))
+		lastModified = string(meta.Get(lastModifiedKey))
+		return nil
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return err
+	}
+	if lastSource == source {
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		// The remote bulk file hasn't changed since our last Sync of this
+		// source; the index is already current, so there's nothing to do.
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("mtg: downloading bulk data from %s: unexpected status %s", source, resp.Status)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(cachePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	return bs.ingestAndMark(f, source, resp.Header.Get("Etag"), resp.Header.Get("Last-Modified"))
+}
+
+func (bs *BulkStore) ingestAndMark(r io.Reader, source, etag, lastModified string) error {
+	if err := bs.Ingest(r); err != nil {
+		return err
+	}
+
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(metaBucket)
+		if err := meta.Put(sourceKey, []byte(source)); err != nil {
+			return err
+		}
+		if err := meta.Put(etagKey, []byte(etag)); err != nil {
+			return err
+		}
+		if err := meta.Put(lastModifiedKey, []byte(lastModified)); err != nil {
+			return err
+		}
+		return meta.Put(lastSyncedKey, []byte(time.Now().Format(time.RFC3339)))
+	})
+}
+
+// bulkCachePath returns the on-disk location Sync caches source's body at,
+// rooted under the OS cache dir (e.g. ~/.cache/mtg-sdk-go on Linux).
+func bulkCachePath(source string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	name := strings.NewReplacer("/", "_", ":", "_").Replace(source)
+	return filepath.Join(cacheDir, "mtg-sdk-go", name+".json"), nil
+}
+
+// FetchAll implements Backend by scanning the whole index for cards that
+// match values, ordering them by values["orderBy"] with sortCardsByColumn.
+func (bs *BulkStore) FetchAll(ctx context.Context, values map[string]string) ([]*Card, error) {
+	var matched []*Card
+	err := bs.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(cardsBucket).ForEach(func(_, v []byte) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			card, ok, err := decodeIfMatches(v, values)
+			if err != nil {
+				return err
+			}
+			if ok {
+				matched = append(matched, card)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortCardsByColumn(matched, values["orderBy"])
+	return matched, nil
+}
+
+// fetchAllCached is FetchAll with its result memoized per distinct values,
+// so a caller that pages through the same query (values is otherwise
+// identical across calls; only pageNum/pageSize vary) pays for one full
+// index scan instead of one per page. The cache is invalidated whenever the
+// index is re-ingested.
+func (bs *BulkStore) fetchAllCached(ctx context.Context, values map[string]string) ([]*Card, error) {
+	key := valuesCacheKey(values)
+
+	bs.scanMu.Lock()
+	if all, ok := bs.scanCache[key]; ok {
+		bs.scanMu.Unlock()
+		return all, nil
+	}
+	bs.scanMu.Unlock()
+
+	all, err := bs.FetchAll(ctx, values)
+	if err != nil {
+		return nil, err
+	}
+
+	bs.scanMu.Lock()
+	if bs.scanCache == nil {
+		bs.scanCache = make(map[string][]*Card)
+	}
+	bs.scanCache[key] = all
+	bs.scanMu.Unlock()
+
+	return all, nil
+}
+
+// valuesCacheKey builds a canonical cache key for a values map, independent
+// of Go's random map iteration order.
+func valuesCacheKey(values map[string]string) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(values[k])
+		b.WriteByte('\x00')
+	}
+	return b.String()
+}
+
+// FetchPage implements Backend by slicing the matching cards to the
+// requested page. The scanned-and-filtered set for values is cached so that
+// repeated calls for the same values (as CardIter/PageIter make while
+// walking a large result set page by page) don't each re-scan the whole
+// index from scratch.
+func (bs *BulkStore) FetchPage(ctx context.Context, values map[string]string, pageNum, pageSize int) ([]*Card, int, error) {
+	all, err := bs.fetchAllCached(ctx, values)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	start := (pageNum - 1) * pageSize
+	if start < 0 || start >= len(all) {
+		return nil, len(all), nil
+	}
+	end := start + pageSize
+	if end > len(all) {
+		end = len(all)
+	}
+	return all[start:end], len(all), nil
+}
+
+// FetchRandom implements Backend by shuffling the matching cards and
+// returning the first count of them.
+func (bs *BulkStore) FetchRandom(ctx context.Context, values map[string]string, count int) ([]*Card, error) {
+	all, err := bs.FetchAll(ctx, values)
+	if err != nil {
+		return nil, err
+	}
+	rand.Shuffle(len(all), func(i, j int) { all[i], all[j] = all[j], all[i] })
+	if count < 0 {
+		count = 0
+	}
+	if count < len(all) {
+		all = all[:count]
+	}
+	return all, nil
+}
+
+// FetchCursor implements Backend. The local index has no Link-header
+// pagination protocol to walk, so cursor is simply the next page number to
+// scan (as a string); it reuses the same scanned-and-cached set FetchPage
+// does, so a sequential walk of cursors still costs one full index scan.
+func (bs *BulkStore) FetchCursor(ctx context.Context, values map[string]string, cursor string) ([]*Card, string, error) {
+	pageNum := 1
+	if cursor != "" {
+		n, err := strconv.Atoi(cursor)
+		if err != nil {
+			return nil, "", fmt.Errorf("mtg: invalid cursor %q", cursor)
+		}
+		pageNum = n
+	}
+
+	cards, total, err := bs.FetchPage(ctx, values, pageNum, iterPageSize)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(cards) == 0 || pageNum*iterPageSize >= total {
+		return cards, "", nil
+	}
+	return cards, strconv.Itoa(pageNum + 1), nil
+}
+
+// decodeIfMatches unmarshals raw both as a generic field map (to test
+// against values) and, on a match, as a *Card to return to the caller.
+func decodeIfMatches(raw []byte, values map[string]string) (*Card, bool, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, false, err
+	}
+	if !matchesFilters(fields, values) {
+		return nil, false, nil
+	}
+
+	var card Card
+	if err := json.Unmarshal(raw, &card); err != nil {
+		return nil, false, err
+	}
+	return &card, true, nil
+}
+
+// matchesFilters reports whether fields satisfies every column=value clause
+// in values. A filter value may be a comma-separated OR list. A string field
+// matches when it equals one of the listed values case-insensitively or
+// contains one as a case-insensitive substring; an array field matches when
+// one of its string items equals one of them case-insensitively. Fields of
+// any other type (numbers such as cmc, objects such as legalities) and
+// columns the card record does not carry (such as gameFormat and legality)
+// never match, and comparator prefixes ("gte4") and "-" negation are
+// compared as literal text.
+func matchesFilters(fields map[string]interface{}, values map[string]string) bool {
+	for column, want := range values {
+		switch column {
+		case "orderBy", "page", "pageSize", "random":
+			continue
+		}
+
+		if !fieldMatches(fields[column], want) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortCardsByColumn sorts cards in place by the given column, comparing each
+// card's value as a string: numbers by their decimal form (so 10 sorts
+// before 9), and values that are neither string nor number as "". An empty
+// column leaves the order untouched.
+func sortCardsByColumn(cards []*Card, column string) {
+	if column == "" {
+		return
+	}
+
+	type keyedCard struct {
+		card *Card
+		key  string
+	}
+	keyed := make([]keyedCard, len(cards))
+	for i, c := range cards {
+		keyed[i] = keyedCard{card: c, key: fieldSortKey(c, column)}
+	}
+
+	sort.SliceStable(keyed, func(i, j int) bool {
+		return keyed[i].key < keyed[j].key
+	})
+	for i, kc := range keyed {
+		cards[i] = kc.card
+	}
+}
+
+// fieldSortKey returns card's column value as a comparable string, re-using
+// the same JSON decoding matchesFilters relies on so it sees the same shape
+// of data (including numeric fields, compared as zero-padded-free strings
+// is good enough for the offline backend's best-effort ordering).
+func fieldSortKey(card *Card, column string) string {
+	raw, err := json.Marshal(card)
+	if err != nil {
+		return ""
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return ""
+	}
+
+	switch v := fields[column].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+func fieldMatches(field interface{}, want string) bool {
+	wantParts := strings.Split(want, ",")
+
+	switch v := field.(type) {
+	case string:
+		for _, w := range wantParts {
+			if strings.EqualFold(strings.TrimSpace(w), v) || strings.Contains(strings.ToLower(v), strings.ToLower(strings.TrimSpace(w))) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, w := range wantParts {
+			for _, item := range v {
+				if s, ok := item.(string); ok && strings.EqualFold(strings.TrimSpace(w), s) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
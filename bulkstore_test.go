@@ -0,0 +1,147 @@
+package mtg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestBulkStore(t *testing.T) *BulkStore {
+	t.Helper()
+
+	bs, err := NewBulkStore(filepath.Join(t.TempDir(), "bulk.db"))
+	if err != nil {
+		t.Fatalf("NewBulkStore: %v", err)
+	}
+	t.Cleanup(func() { bs.Close() })
+	return bs
+}
+
+func TestBulkStoreIngestAndFetchAll(t *testing.T) {
+	bs := newTestBulkStore(t)
+
+	if err := bs.Ingest(strings.NewReader(buildCardsJSON([]string{"1", "2", "3"}))); err != nil {
+		t.Fatalf("Ingest: %v", err)
+	}
+
+	cards, err := bs.FetchAll(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	if got := cardIDs(cards); !equalStrings(got, []string{"1", "2", "3"}) {
+		t.Errorf("FetchAll ids = %v, want [1 2 3]", got)
+	}
+}
+
+func TestBulkStoreIngestReplacesPreviousIndex(t *testing.T) {
+	bs := newTestBulkStore(t)
+
+	if err := bs.Ingest(strings.NewReader(buildCardsJSON([]string{"1", "2"}))); err != nil {
+		t.Fatalf("Ingest: %v", err)
+	}
+	if err := bs.Ingest(strings.NewReader(buildCardsJSON([]string{"3"}))); err != nil {
+		t.Fatalf("Ingest (second): %v", err)
+	}
+
+	cards, err := bs.FetchAll(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	if got := cardIDs(cards); !equalStrings(got, []string{"3"}) {
+		t.Errorf("FetchAll ids = %v, want [3] (stale entries from the first Ingest should be gone)", got)
+	}
+}
+
+func TestBulkStoreSyncSkipsOnNotModified(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Etag", `"v1"`)
+		w.Write([]byte(buildCardsJSON([]string{"1"})))
+	}))
+	defer srv.Close()
+
+	bs := newTestBulkStore(t)
+
+	if err := bs.Sync(context.Background(), srv.URL); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if err := bs.Sync(context.Background(), srv.URL); err != nil {
+		t.Fatalf("Sync (revalidate): %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("got %d requests, want 2 (one miss, one 304 revalidation)", requests)
+	}
+
+	cards, err := bs.FetchAll(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	if len(cards) != 1 {
+		t.Fatalf("got %d cards, want 1", len(cards))
+	}
+}
+
+func TestMatchesFilters(t *testing.T) {
+	fields := map[string]interface{}{
+		"colors": []interface{}{"Red", "Green"},
+		"rarity": "Rare",
+	}
+
+	cases := []struct {
+		name   string
+		values map[string]string
+		want   bool
+	}{
+		{name: "case-insensitive exact match", values: map[string]string{"rarity": "rare"}, want: true},
+		{name: "mismatch", values: map[string]string{"rarity": "common"}, want: false},
+		{name: "array field match", values: map[string]string{"colors": "Green"}, want: true},
+		{name: "array field mismatch", values: map[string]string{"colors": "Blue"}, want: false},
+		{name: "orderBy is not a filter", values: map[string]string{"orderBy": "name"}, want: true},
+		{name: "comma-separated OR list", values: map[string]string{"rarity": "common,rare"}, want: true},
+	}
+	for _, c := range cases {
+		if got := matchesFilters(fields, c.values); got != c.want {
+			t.Errorf("%s: matchesFilters(%v, %v) = %v, want %v", c.name, fields, c.values, got, c.want)
+		}
+	}
+}
+
+func TestSortCardsByColumn(t *testing.T) {
+	cards := []*Card{
+		{ID: "1", Name: "Banana"},
+		{ID: "2", Name: "Apple"},
+		{ID: "3", Name: "Cherry"},
+	}
+
+	sortCardsByColumn(cards, "name")
+
+	got := make([]string, len(cards))
+	for i, c := range cards {
+		got[i] = c.Name
+	}
+	want := []string{"Apple", "Banana", "Cherry"}
+	if !equalStrings(got, want) {
+		t.Errorf("sortCardsByColumn order = %v, want %v", got, want)
+	}
+}
+
+func TestSortCardsByColumnEmptyColumnIsNoop(t *testing.T) {
+	cards := []*Card{
+		{ID: "1", Name: "Banana"},
+		{ID: "2", Name: "Apple"},
+	}
+
+	sortCardsByColumn(cards, "")
+
+	if cards[0].Name != "Banana" || cards[1].Name != "Apple" {
+		t.Errorf("sortCardsByColumn with an empty column reordered cards: %v", cards)
+	}
+}
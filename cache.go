@@ -0,0 +1,138 @@
+package mtg
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Cache stores raw API responses keyed by their fully-encoded request URL,
+// so fetchCards can revalidate with If-None-Match/If-Modified-Since instead
+// of re-downloading unchanged pages. Implementations must be safe for
+// concurrent use. See NewMemoryCache and NewDiskCache.
+type Cache interface {
+	// Get returns the cached body and response header for key, if present.
+	Get(key string) (body []byte, header http.Header, ok bool)
+	// Set stores body and header under key, evicting older entries if the
+	// implementation is capacity-bounded.
+	Set(key string, body []byte, header http.Header)
+}
+
+// cacheEntry is the value half of a Cache, shared by both implementations.
+type cacheEntry struct {
+	body   []byte
+	header http.Header
+}
+
+// memoryCache is an in-memory, capacity-bounded LRU Cache.
+type memoryCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry cacheEntry
+}
+
+// NewMemoryCache returns a Cache that keeps the capacity most-recently-used
+// entries in memory and discards the rest.
+func NewMemoryCache(capacity int) Cache {
+	return &memoryCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *memoryCache) Get(key string) ([]byte, http.Header, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, nil, false
+	}
+	c.ll.MoveToFront(el)
+	item := el.Value.(*memoryCacheItem)
+	return item.entry.body, item.entry.header, true
+}
+
+func (c *memoryCache) Set(key string, body []byte, header http.Header) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		el.Value.(*memoryCacheItem).entry = cacheEntry{body: body, header: header}
+		return
+	}
+
+	el := c.ll.PushFront(&memoryCacheItem{key: key, entry: cacheEntry{body: body, header: header}})
+	c.items[key] = el
+
+	for c.capacity > 0 && c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest == nil {
+			break
+		}
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*memoryCacheItem).key)
+	}
+}
+
+// diskCache is a Cache rooted at a directory on disk, so entries survive
+// across process restarts. Each entry is one JSON file named after the
+// sha256 of its key.
+type diskCache struct {
+	dir string
+}
+
+// diskCacheFile is the on-disk representation of one cache entry.
+type diskCacheFile struct {
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// NewDiskCache returns a Cache rooted at dir, creating it if necessary. A
+// natural place to root it is an OS cache dir, e.g.
+// filepath.Join(os.UserCacheDir(), "mtg-sdk-go", "responses").
+func NewDiskCache(dir string) (Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &diskCache{dir: dir}, nil
+}
+
+func (c *diskCache) Get(key string) ([]byte, http.Header, bool) {
+	raw, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, nil, false
+	}
+
+	var f diskCacheFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, nil, false
+	}
+	return f.Body, f.Header, true
+}
+
+func (c *diskCache) Set(key string, body []byte, header http.Header) {
+	raw, err := json.Marshal(diskCacheFile{Header: header, Body: body})
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(c.path(key), raw, 0o644)
+}
+
+func (c *diskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
@@ -0,0 +1,62 @@
+package mtg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewMemoryCache(2)
+	c.Set("a", []byte("a"), http.Header{})
+	c.Set("b", []byte("b"), http.Header{})
+	c.Set("c", []byte("c"), http.Header{})
+
+	if _, _, ok := c.Get("a"); ok {
+		t.Error("expected \"a\" to have been evicted")
+	}
+	if _, _, ok := c.Get("b"); !ok {
+		t.Error("expected \"b\" to still be cached")
+	}
+	if _, _, ok := c.Get("c"); !ok {
+		t.Error("expected \"c\" to still be cached")
+	}
+}
+
+func TestClientRevalidatesWithETag(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Etag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(buildCardsJSON([]string{"1"})))
+	}))
+	defer srv.Close()
+
+	client := NewClient(WithBaseURL(srv.URL+"/"), WithLimiter(nil), WithCache(NewMemoryCache(10)))
+	q := client.QueryWithContext(context.Background())
+
+	cards, err := q.All()
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(cards) != 1 {
+		t.Fatalf("got %d cards, want 1", len(cards))
+	}
+
+	cards, err = q.All()
+	if err != nil {
+		t.Fatalf("All (revalidated): %v", err)
+	}
+	if len(cards) != 1 {
+		t.Fatalf("got %d cards on revalidation, want 1", len(cards))
+	}
+	if requests != 2 {
+		t.Errorf("got %d requests, want 2 (one miss, one 304 revalidation)", requests)
+	}
+}
@@ -0,0 +1,289 @@
+package mtg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	queryURL = "https://api.magicthegathering.io/v1/"
+
+	// defaultUserAgent is sent with every request unless overridden with WithUserAgent.
+	defaultUserAgent = "mtg-sdk-go"
+)
+
+var linkRE = regexp.MustCompile(`<(.*)>; rel="(.*)"`)
+
+// Client talks to the magicthegathering.io API. Use NewClient to build one;
+// the zero value is not usable. Client implements Backend.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	userAgent  string
+	limiter    *rate.Limiter
+	cache      Cache
+}
+
+// Option configures a Client. See WithHTTPClient, WithLimiter, WithBaseURL,
+// WithUserAgent and WithCache.
+type Option func(*Client)
+
+// WithHTTPClient sets the http.Client used to perform requests. Defaults to
+// http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithLimiter sets the rate limiter that gates every outgoing request. Pass
+// nil to disable rate limiting entirely. Defaults to 10 requests/second;
+// note that sustained, this is well over the API's 5000 requests/hour cap
+// (10/s sustained is ~36000/hour), so long-running callers that expect to
+// stay under the hourly cap should pass a more conservative limiter.
+func WithLimiter(l *rate.Limiter) Option {
+	return func(c *Client) { c.limiter = l }
+}
+
+// WithBaseURL overrides the API base URL, e.g. to point at a mock server in
+// tests. Defaults to queryURL.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) { c.baseURL = baseURL }
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.userAgent = ua }
+}
+
+// WithCache sets the response cache used to revalidate (and skip
+// re-downloading) unchanged pages via ETag/Last-Modified. Unset by default,
+// meaning no caching takes place.
+func WithCache(cache Cache) Option {
+	return func(c *Client) { c.cache = cache }
+}
+
+// NewClient builds a Client with the given options applied over the
+// defaults (http.DefaultClient, queryURL, a 10 req/s limiter — see
+// WithLimiter for how that default relates to the API's hourly cap).
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		httpClient: http.DefaultClient,
+		baseURL:    queryURL,
+		userAgent:  defaultUserAgent,
+		limiter:    rate.NewLimiter(10, 10),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// defaultClient backs the package-level NewQuery so existing callers keep
+// working without having to construct a Client themselves.
+var defaultClient = NewClient()
+
+// QueryWithContext creates a new Query bound to this Client and ctx. All of
+// the Query's paginated methods (All, Page, PageS, Random) wait on the
+// Client's rate limiter and stop early once ctx is canceled.
+func (c *Client) QueryWithContext(ctx context.Context) Query {
+	return query{backend: c, ctx: ctx, values: make(map[string]string)}
+}
+
+// FetchAll implements Backend by walking every page of values via
+// FetchCursor, following the Link: rel="next" header until it's exhausted.
+func (c *Client) FetchAll(ctx context.Context, values map[string]string) ([]*Card, error) {
+	var allCards []*Card
+
+	cursor := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		cards, next, err := c.FetchCursor(ctx, values, cursor)
+		if err != nil {
+			return nil, err
+		}
+		allCards = append(allCards, cards...)
+
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	return allCards, nil
+}
+
+// FetchCursor implements Backend. cursor is the Link: rel="next" URL
+// returned by the previous call (or "" to start from the beginning), so
+// this follows exactly the pagination the API itself exposes.
+func (c *Client) FetchCursor(ctx context.Context, values map[string]string, cursor string) ([]*Card, string, error) {
+	rawURL := cursor
+	if rawURL == "" {
+		queryVals := make(url.Values)
+		for k, v := range values {
+			queryVals.Set(k, v)
+		}
+		rawURL = c.baseURL + "cards?" + queryVals.Encode()
+	}
+
+	cards, header, err := c.fetchCards(ctx, rawURL)
+	if err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if linkH, ok := header["Link"]; ok {
+		parts := strings.Split(linkH[0], ",")
+		for _, link := range parts {
+			match := linkRE.FindStringSubmatch(link)
+			if match != nil && match[2] == "next" {
+				next = match[1]
+			}
+		}
+	}
+
+	return cards, next, nil
+}
+
+// FetchPage implements Backend by fetching a single page of values.
+func (c *Client) FetchPage(ctx context.Context, values map[string]string, pageNum, pageSize int) ([]*Card, int, error) {
+	queryVals := make(url.Values)
+	for k, v := range values {
+		queryVals.Set(k, v)
+	}
+
+	queryVals.Set("page", strconv.Itoa(pageNum))
+	queryVals.Set("pageSize", strconv.Itoa(pageSize))
+
+	rawURL := c.baseURL + "cards?" + queryVals.Encode()
+	cards, header, err := c.fetchCards(ctx, rawURL)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	totalCardCount := len(cards)
+	if totals, ok := header["Total-Count"]; ok && len(totals) > 0 {
+		if totalCardCount, err = strconv.Atoi(totals[0]); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return cards, totalCardCount, nil
+}
+
+// FetchRandom implements Backend by asking the API for count random cards.
+func (c *Client) FetchRandom(ctx context.Context, values map[string]string, count int) ([]*Card, error) {
+	queryVals := make(url.Values)
+	for k, v := range values {
+		queryVals.Set(k, v)
+	}
+
+	queryVals.Set("random", "true")
+	queryVals.Set("pageSize", strconv.Itoa(count))
+
+	rawURL := c.baseURL + "cards?" + queryVals.Encode()
+	cards, _, err := c.fetchCards(ctx, rawURL)
+	return cards, err
+}
+
+// fetchCards performs a GET against rawURL, waiting on the client's rate
+// limiter (if any) and honoring ctx cancellation before the request is sent.
+func (c *Client) fetchCards(ctx context.Context, rawURL string) ([]*Card, http.Header, error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	var cachedBody []byte
+	var cachedHeader http.Header
+	if c.cache != nil {
+		if body, header, ok := c.cache.Get(rawURL); ok {
+			cachedBody, cachedHeader = body, header
+			if etag := header.Get("Etag"); etag != "" {
+				req.Header.Set("If-None-Match", etag)
+			}
+			if lastMod := header.Get("Last-Modified"); lastMod != "" {
+				req.Header.Set("If-Modified-Since", lastMod)
+			}
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// resp.Body is io.ReadCloser
+	bdy := resp.Body
+	defer bdy.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cachedBody != nil {
+		cards, err := decodeCards(bytes.NewReader(cachedBody))
+		if err != nil {
+			return nil, nil, err
+		}
+		return cards, cachedHeader, nil
+	}
+
+	if err := checkError(resp); err != nil {
+		return nil, nil, err
+	}
+
+	raw, err := io.ReadAll(bdy)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.cache != nil {
+		c.cache.Set(rawURL, raw, resp.Header)
+	}
+
+	cards, err := decodeCards(bytes.NewReader(raw))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cards, resp.Header, nil
+}
+
+// decodeCards unmarshals resp body to cardResponse struct.
+func decodeCards(reader io.Reader) ([]*Card, error) {
+	asBytes, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var cardResp cardResponse
+	if err := json.Unmarshal(asBytes, &cardResp); err != nil {
+		return nil, err
+	}
+	/*
+		decoder := json.NewDecoder(reader)
+		err = decoder.Decode(&cardResp)
+		if err != nil {
+			return nil, err
+		}
+	*/
+	if cardResp.Card != nil {
+		return []*Card{cardResp.Card}, nil
+	}
+
+	return cardResp.Cards, nil
+}
@@ -0,0 +1,91 @@
+package mtg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestNewQueryUsesDefaultClient(t *testing.T) {
+	q := NewQuery()
+
+	qq, ok := q.(query)
+	if !ok {
+		t.Fatalf("NewQuery() returned %T, want query", q)
+	}
+	if qq.backend != Backend(defaultClient) {
+		t.Error("NewQuery()'s backend is not defaultClient")
+	}
+}
+
+func TestClientFetchAllRespectsCanceledContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(buildCardsJSON([]string{"1"})))
+	}))
+	defer srv.Close()
+
+	client := NewClient(WithBaseURL(srv.URL+"/"), WithLimiter(nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.FetchAll(ctx, nil); err == nil {
+		t.Fatal("FetchAll with an already-canceled context: expected error, got nil")
+	}
+}
+
+func TestClientFetchCursorWaitsOnLimiter(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(buildCardsJSON([]string{"1"})))
+	}))
+	defer srv.Close()
+
+	limiter := rate.NewLimiter(rate.Every(50*time.Millisecond), 1)
+	client := NewClient(WithBaseURL(srv.URL+"/"), WithLimiter(limiter))
+
+	start := time.Now()
+	if _, _, err := client.FetchCursor(context.Background(), nil, ""); err != nil {
+		t.Fatalf("FetchCursor: %v", err)
+	}
+	if _, _, err := client.FetchCursor(context.Background(), nil, ""); err != nil {
+		t.Fatalf("FetchCursor: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("two requests through a 1-per-50ms limiter took %v, want at least ~50ms", elapsed)
+	}
+	if requests != 2 {
+		t.Errorf("got %d requests, want 2", requests)
+	}
+}
+
+func TestClientFetchCursorFailsFastWhenLimiterBlocksPastContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(buildCardsJSON([]string{"1"})))
+	}))
+	defer srv.Close()
+
+	// A limiter with no burst never lets the first request through: Wait
+	// fails at once because one token exceeds the burst of 0, so the call
+	// errors before the short-lived context's deadline and the HTTP round
+	// trip never happens.
+	limiter := rate.NewLimiter(rate.Every(time.Hour), 0)
+	client := NewClient(WithBaseURL(srv.URL+"/"), WithLimiter(limiter))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, _, err := client.FetchCursor(ctx, nil, ""); err == nil {
+		t.Fatal("FetchCursor blocked on an exhausted limiter past its context deadline: expected error, got nil")
+	}
+}
@@ -0,0 +1,219 @@
+package mtg
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Format identifies a constructed or limited format a card can be legal,
+// banned or restricted in, for use with LegalIn, BannedIn, RestrictedIn,
+// LegalInAll and LegalInAny.
+type Format string
+
+const (
+	FormatStandard        Format = "Standard"
+	FormatPioneer         Format = "Pioneer"
+	FormatModern          Format = "Modern"
+	FormatLegacy          Format = "Legacy"
+	FormatVintage         Format = "Vintage"
+	FormatCommander       Format = "Commander"
+	FormatPauper          Format = "Pauper"
+	FormatBrawl           Format = "Brawl"
+	FormatHistoricBrawl   Format = "Historic Brawl"
+	FormatAlchemy         Format = "Alchemy"
+	FormatExplorer        Format = "Explorer"
+	FormatHistoric        Format = "Historic"
+	FormatPauperCommander Format = "Pauper Commander"
+	FormatDuel            Format = "Duel"
+	FormatOldschool       Format = "Oldschool"
+	FormatPremodern       Format = "Premodern"
+)
+
+// allFormats lists every Format constant, for formatByName to search.
+var allFormats = []Format{
+	FormatStandard, FormatPioneer, FormatModern, FormatLegacy, FormatVintage,
+	FormatCommander, FormatPauper, FormatBrawl, FormatHistoricBrawl, FormatAlchemy,
+	FormatExplorer, FormatHistoric, FormatPauperCommander, FormatDuel, FormatOldschool,
+	FormatPremodern,
+}
+
+// formatByName resolves name to one of the Format constants, matching
+// case-insensitively and ignoring spaces so both "historicbrawl" and
+// "Historic Brawl" resolve to FormatHistoricBrawl.
+func formatByName(name string) (Format, bool) {
+	key := strings.ToLower(strings.ReplaceAll(name, " ", ""))
+	for _, f := range allFormats {
+		if strings.ToLower(strings.ReplaceAll(string(f), " ", "")) == key {
+			return f, true
+		}
+	}
+	return "", false
+}
+
+// LegalIn filters the query down to cards that are legal in format.
+func (q query) LegalIn(format Format) Query {
+	return q.Where(CardGameFormat, string(format)).Where(CardLegality, "Legal")
+}
+
+// BannedIn filters the query down to cards that are banned in format.
+func (q query) BannedIn(format Format) Query {
+	return q.Where(CardGameFormat, string(format)).Where(CardLegality, "Banned")
+}
+
+// RestrictedIn filters the query down to cards that are restricted in format.
+func (q query) RestrictedIn(format Format) Query {
+	return q.Where(CardGameFormat, string(format)).Where(CardLegality, "Restricted")
+}
+
+// LegalInAll returns a Query for cards that are legal in every one of
+// formats. Since the upstream API only accepts one gameFormat per request,
+// this fans out one legal-in request per format (through the same Backend,
+// so the Backend's own rate limiting still applies) and intersects the
+// resulting card IDs client-side. The intersection is resolved eagerly; the
+// returned Query's All/Page/PageS/Random serve it without further requests.
+func (q query) LegalInAll(formats ...Format) Query {
+	return q.fanOutFormats(formats, intersectCardSets)
+}
+
+// LegalInAny returns a Query for cards that are legal in at least one of
+// formats. See LegalInAll for how the fan-out and result are resolved.
+func (q query) LegalInAny(formats ...Format) Query {
+	return q.fanOutFormats(formats, unionCardSets)
+}
+
+// fanOutFormats issues one LegalIn(format).All() per format concurrently,
+// combines the resulting card sets with combine, and wraps the combined
+// cards in a staticQuery.
+func (q query) fanOutFormats(formats []Format, combine func([][]*Card) []*Card) Query {
+	results := make([][]*Card, len(formats))
+	errs := make([]error, len(formats))
+
+	var wg sync.WaitGroup
+	for i, format := range formats {
+		wg.Add(1)
+		go func(i int, format Format) {
+			defer wg.Done()
+			cards, err := q.Copy().LegalIn(format).All()
+			results[i] = cards
+			errs[i] = err
+		}(i, format)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return staticQuery{ctx: q.ctx, err: err}
+		}
+	}
+
+	return staticQuery{ctx: q.ctx, cards: combine(results)}
+}
+
+// intersectCardSets returns the cards present in every set, keyed by Card.ID.
+func intersectCardSets(sets [][]*Card) []*Card {
+	if len(sets) == 0 {
+		return nil
+	}
+
+	counts := make(map[string]int, len(sets[0]))
+	byID := make(map[string]*Card, len(sets[0]))
+	for _, cards := range sets {
+		seen := make(map[string]bool, len(cards))
+		for _, c := range cards {
+			if seen[c.ID] {
+				continue
+			}
+			seen[c.ID] = true
+			counts[c.ID]++
+			byID[c.ID] = c
+		}
+	}
+
+	var out []*Card
+	for id, n := range counts {
+		if n == len(sets) {
+			out = append(out, byID[id])
+		}
+	}
+	return out
+}
+
+// unionCardSets returns the distinct cards across all sets, keyed by Card.ID.
+func unionCardSets(sets [][]*Card) []*Card {
+	seen := make(map[string]bool)
+	var out []*Card
+	for _, cards := range sets {
+		for _, c := range cards {
+			if seen[c.ID] {
+				continue
+			}
+			seen[c.ID] = true
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// staticQuery is a Query over an already-resolved, fixed slice of cards. It
+// backs LegalInAll/LegalInAny, whose results are combined client-side up
+// front rather than fetched lazily.
+type staticQuery struct {
+	ctx   context.Context
+	cards []*Card
+	err   error
+}
+
+func (q staticQuery) Where(cardColumn, string) Query { return q }
+func (q staticQuery) OrderBy(cardColumn) Query       { return q }
+
+func (q staticQuery) Copy() Query {
+	cards := make([]*Card, len(q.cards))
+	copy(cards, q.cards)
+	return staticQuery{ctx: q.ctx, cards: cards, err: q.err}
+}
+
+func (q staticQuery) All() ([]*Card, error) {
+	return q.cards, q.err
+}
+
+func (q staticQuery) Page(pageNum int) ([]*Card, int, error) {
+	return q.PageS(pageNum, 100)
+}
+
+func (q staticQuery) PageS(pageNum int, pageSize int) ([]*Card, int, error) {
+	if q.err != nil {
+		return nil, 0, q.err
+	}
+
+	start := (pageNum - 1) * pageSize
+	if start < 0 || start >= len(q.cards) {
+		return nil, len(q.cards), nil
+	}
+	end := start + pageSize
+	if end > len(q.cards) {
+		end = len(q.cards)
+	}
+	return q.cards[start:end], len(q.cards), nil
+}
+
+func (q staticQuery) Random(count int) ([]*Card, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if count < 0 {
+		count = 0
+	}
+	if count > len(q.cards) {
+		count = len(q.cards)
+	}
+	return q.cards[:count], nil
+}
+
+// LegalIn, BannedIn and RestrictedIn have no further requests to make once a
+// staticQuery has been resolved, so they're no-ops that return q unchanged.
+func (q staticQuery) LegalIn(Format) Query       { return q }
+func (q staticQuery) BannedIn(Format) Query      { return q }
+func (q staticQuery) RestrictedIn(Format) Query  { return q }
+func (q staticQuery) LegalInAll(...Format) Query { return q }
+func (q staticQuery) LegalInAny(...Format) Query { return q }
@@ -0,0 +1,98 @@
+package mtg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+)
+
+// formatCardSets maps gameFormat query values to the card IDs the fake API
+// should return for a legal-in-that-format request.
+var formatCardSets = map[string][]string{
+	"Modern": {"1", "2", "3"},
+	"Legacy": {"2", "3", "4"},
+}
+
+func newFormatTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		format := r.URL.Query().Get("gameFormat")
+		ids := formatCardSets[format]
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(buildCardsJSON(ids)))
+	}))
+}
+
+func buildCardsJSON(ids []string) string {
+	out := `{"cards":[`
+	for i, id := range ids {
+		if i > 0 {
+			out += ","
+		}
+		out += `{"id":"` + id + `","name":"Card ` + id + `"}`
+	}
+	out += `]}`
+	return out
+}
+
+func cardIDs(cards []*Card) []string {
+	ids := make([]string, len(cards))
+	for i, c := range cards {
+		ids[i] = c.ID
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestLegalInAllIntersects(t *testing.T) {
+	srv := newFormatTestServer(t)
+	defer srv.Close()
+
+	client := NewClient(WithBaseURL(srv.URL+"/"), WithLimiter(nil))
+	q := client.QueryWithContext(context.Background())
+
+	cards, err := q.LegalInAll(FormatModern, FormatLegacy).All()
+	if err != nil {
+		t.Fatalf("LegalInAll: %v", err)
+	}
+
+	got := cardIDs(cards)
+	want := []string{"2", "3"}
+	if !equalStrings(got, want) {
+		t.Errorf("LegalInAll ids = %v, want %v", got, want)
+	}
+}
+
+func TestLegalInAnyUnions(t *testing.T) {
+	srv := newFormatTestServer(t)
+	defer srv.Close()
+
+	client := NewClient(WithBaseURL(srv.URL+"/"), WithLimiter(nil))
+	q := client.QueryWithContext(context.Background())
+
+	cards, err := q.LegalInAny(FormatModern, FormatLegacy).All()
+	if err != nil {
+		t.Fatalf("LegalInAny: %v", err)
+	}
+
+	got := cardIDs(cards)
+	want := []string{"1", "2", "3", "4"}
+	if !equalStrings(got, want) {
+		t.Errorf("LegalInAny ids = %v, want %v", got, want)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
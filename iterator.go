@@ -0,0 +1,225 @@
+package mtg
+
+import "context"
+
+const iterPageSize = 100
+
+// pageResult is one fetched page passed from the prefetching goroutine to
+// the iterator the caller is draining.
+type pageResult struct {
+	cards []*Card
+	err   error
+}
+
+// pagePump fetches successive pages of values from backend in a background
+// goroutine, stopping once the backend returns no next cursor (or an
+// error), or ctx is canceled. Results are delivered one at a time over a buffered channel so
+// the next page is fetched while the caller drains the current one.
+type pagePump struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	results chan pageResult
+}
+
+func newPagePump(ctx context.Context, backend Backend, values map[string]string) *pagePump {
+	ctx, cancel := context.WithCancel(ctx)
+	p := &pagePump{
+		ctx:     ctx,
+		cancel:  cancel,
+		results: make(chan pageResult, 1),
+	}
+	go p.run(backend, values)
+	return p
+}
+
+// run walks backend via FetchCursor, the same cursor-following pagination
+// FetchAll uses, so Iter/IterPages see exactly the pages the backend itself
+// would walk (the Link: rel="next" header, for Client) rather than an
+// independently-guessed page sequence.
+func (p *pagePump) run(backend Backend, values map[string]string) {
+	defer close(p.results)
+
+	cursor := ""
+	for {
+		cards, next, err := backend.FetchCursor(p.ctx, values, cursor)
+
+		select {
+		case p.results <- pageResult{cards: cards, err: err}:
+		case <-p.ctx.Done():
+			return
+		}
+
+		if err != nil || next == "" {
+			return
+		}
+		cursor = next
+	}
+}
+
+// next blocks until the next page is ready, or returns ok=false once the
+// pump is exhausted.
+func (p *pagePump) next() (pageResult, bool) {
+	res, ok := <-p.results
+	return res, ok
+}
+
+func (p *pagePump) close() {
+	p.cancel()
+}
+
+// CardIter streams the cards matching a Query one at a time, fetching pages
+// in the background so a long walk over a large result set (e.g. t:creature,
+// which can match tens of thousands of cards) doesn't have to hold every
+// page in memory at once. Build one with Query.Iter.
+type CardIter struct {
+	pump   *pagePump
+	buf    []*Card
+	idx    int
+	cur    *Card
+	err    error
+	static []*Card // used instead of pump when iterating an already-resolved staticQuery
+}
+
+// Iter returns a CardIter over every card matching q, prefetching pages in
+// the background and honoring ctx cancellation between them.
+func (q query) Iter(ctx context.Context) *CardIter {
+	return &CardIter{pump: newPagePump(ctx, q.backend, q.values)}
+}
+
+// Iter returns a CardIter over q's already-resolved cards; there's nothing
+// left to fetch, so it streams from memory instead of a pagePump.
+func (q staticQuery) Iter(context.Context) *CardIter {
+	return &CardIter{static: q.cards, err: q.err}
+}
+
+// Next advances the iterator to the next card, fetching more pages as
+// needed. It returns false once the result set, or ctx, is exhausted, or an
+// error occurred; check Err to tell the two apart.
+func (it *CardIter) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	if it.pump == nil {
+		if it.idx >= len(it.static) {
+			return false
+		}
+		it.cur = it.static[it.idx]
+		it.idx++
+		return true
+	}
+
+	for it.idx >= len(it.buf) {
+		res, ok := it.pump.next()
+		if !ok {
+			return false
+		}
+		if res.err != nil {
+			it.err = res.err
+			return false
+		}
+		if len(res.cards) == 0 {
+			return false
+		}
+		it.buf = res.cards
+		it.idx = 0
+	}
+
+	it.cur = it.buf[it.idx]
+	it.idx++
+	return true
+}
+
+// Card returns the card Next just advanced to.
+func (it *CardIter) Card() *Card {
+	return it.cur
+}
+
+// Err returns the first error encountered while fetching, if any.
+func (it *CardIter) Err() error {
+	return it.err
+}
+
+// Close stops the background prefetch goroutine, if any. Callers that don't
+// drain Next to completion must call Close to avoid leaking it.
+func (it *CardIter) Close() {
+	if it.pump != nil {
+		it.pump.close()
+	}
+}
+
+// PageIter streams the cards matching a Query one page at a time, for
+// callers that want to work in batches rather than card-by-card. Build one
+// with Query.IterPages.
+type PageIter struct {
+	pump   *pagePump
+	cur    []*Card
+	err    error
+	static []*Card // used instead of pump when iterating an already-resolved staticQuery
+	done   bool
+}
+
+// IterPages returns a PageIter over every page matching q, prefetching the
+// next page in the background while the caller works through the current
+// one.
+func (q query) IterPages(ctx context.Context) *PageIter {
+	return &PageIter{pump: newPagePump(ctx, q.backend, q.values)}
+}
+
+// IterPages returns a PageIter over q's already-resolved cards as a single
+// page; there's nothing left to fetch, so it streams from memory instead of
+// a pagePump.
+func (q staticQuery) IterPages(context.Context) *PageIter {
+	return &PageIter{static: q.cards, err: q.err}
+}
+
+// Next advances the iterator to the next page. It returns false once the
+// result set, or ctx, is exhausted, or an error occurred; check Err to tell
+// the two apart.
+func (it *PageIter) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	if it.pump == nil {
+		if it.done || len(it.static) == 0 {
+			return false
+		}
+		it.cur = it.static
+		it.done = true
+		return true
+	}
+
+	res, ok := it.pump.next()
+	if !ok {
+		return false
+	}
+	if res.err != nil {
+		it.err = res.err
+		return false
+	}
+	if len(res.cards) == 0 {
+		return false
+	}
+
+	it.cur = res.cards
+	return true
+}
+
+// Page returns the page Next just advanced to.
+func (it *PageIter) Page() []*Card {
+	return it.cur
+}
+
+// Err returns the first error encountered while fetching, if any.
+func (it *PageIter) Err() error {
+	return it.err
+}
+
+// Close stops the background prefetch goroutine, if any. Callers that don't
+// drain Next to completion must call Close to avoid leaking it.
+func (it *PageIter) Close() {
+	if it.pump != nil {
+		it.pump.close()
+	}
+}
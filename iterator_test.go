@@ -0,0 +1,159 @@
+package mtg
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+// fakeBackend is a minimal Backend that serves a fixed sequence of pages,
+// for exercising pagePump/CardIter/PageIter without a real Client or
+// BulkStore. err, if set, is returned instead of the final page.
+type fakeBackend struct {
+	pages [][]*Card
+	err   error
+	calls int
+}
+
+func (b *fakeBackend) FetchCursor(ctx context.Context, values map[string]string, cursor string) ([]*Card, string, error) {
+	b.calls++
+
+	idx := 0
+	if cursor != "" {
+		n, err := strconv.Atoi(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		idx = n
+	}
+	if idx >= len(b.pages) {
+		return nil, "", nil
+	}
+	if b.err != nil && idx == len(b.pages)-1 {
+		return nil, "", b.err
+	}
+
+	next := ""
+	if idx+1 < len(b.pages) {
+		next = strconv.Itoa(idx + 1)
+	}
+	return b.pages[idx], next, nil
+}
+
+func (b *fakeBackend) FetchAll(ctx context.Context, values map[string]string) ([]*Card, error) {
+	var all []*Card
+	for _, p := range b.pages {
+		all = append(all, p...)
+	}
+	return all, nil
+}
+
+func (b *fakeBackend) FetchPage(ctx context.Context, values map[string]string, pageNum, pageSize int) ([]*Card, int, error) {
+	return nil, 0, errors.New("fakeBackend.FetchPage is not used by these tests")
+}
+
+func (b *fakeBackend) FetchRandom(ctx context.Context, values map[string]string, count int) ([]*Card, error) {
+	return nil, errors.New("fakeBackend.FetchRandom is not used by these tests")
+}
+
+func TestCardIterDrainsAllPages(t *testing.T) {
+	backend := &fakeBackend{pages: [][]*Card{
+		{{ID: "1"}, {ID: "2"}},
+		{{ID: "3"}},
+	}}
+	q := query{backend: backend, ctx: context.Background(), values: map[string]string{}}
+
+	it := q.Iter(context.Background())
+	defer it.Close()
+
+	var got []string
+	for it.Next() {
+		got = append(got, it.Card().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	if !equalStrings(got, []string{"1", "2", "3"}) {
+		t.Errorf("CardIter drained %v, want [1 2 3]", got)
+	}
+	if backend.calls != 2 {
+		t.Errorf("FetchCursor called %d times, want 2", backend.calls)
+	}
+}
+
+func TestCardIterPropagatesError(t *testing.T) {
+	wantErr := errors.New("boom")
+	backend := &fakeBackend{pages: [][]*Card{{{ID: "1"}}}, err: wantErr}
+	q := query{backend: backend, ctx: context.Background(), values: map[string]string{}}
+
+	it := q.Iter(context.Background())
+	defer it.Close()
+
+	for it.Next() {
+	}
+	if it.Err() != wantErr {
+		t.Errorf("Err() = %v, want %v", it.Err(), wantErr)
+	}
+}
+
+func TestPageIterDrainsAllPages(t *testing.T) {
+	backend := &fakeBackend{pages: [][]*Card{
+		{{ID: "1"}, {ID: "2"}},
+		{{ID: "3"}},
+	}}
+	q := query{backend: backend, ctx: context.Background(), values: map[string]string{}}
+
+	it := q.IterPages(context.Background())
+	defer it.Close()
+
+	var pages [][]string
+	for it.Next() {
+		pages = append(pages, cardIDs(it.Page()))
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("got %d pages, want 2", len(pages))
+	}
+	if !equalStrings(pages[0], []string{"1", "2"}) {
+		t.Errorf("page 0 = %v, want [1 2]", pages[0])
+	}
+	if !equalStrings(pages[1], []string{"3"}) {
+		t.Errorf("page 1 = %v, want [3]", pages[1])
+	}
+}
+
+func TestCardIterCloseCancelsPump(t *testing.T) {
+	backend := &fakeBackend{pages: [][]*Card{{{ID: "1"}}, {{ID: "2"}}}}
+	q := query{backend: backend, ctx: context.Background(), values: map[string]string{}}
+
+	it := q.Iter(context.Background())
+	if !it.Next() {
+		t.Fatal("expected at least one card before Close")
+	}
+	it.Close()
+
+	if it.pump.ctx.Err() == nil {
+		t.Error("expected the pump's context to be canceled after Close")
+	}
+}
+
+func TestCardIterOverStaticQuery(t *testing.T) {
+	q := staticQuery{cards: []*Card{{ID: "1"}, {ID: "2"}}}
+
+	it := q.Iter(context.Background())
+	defer it.Close()
+
+	var got []string
+	for it.Next() {
+		got = append(got, it.Card().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	if !equalStrings(got, []string{"1", "2"}) {
+		t.Errorf("CardIter over staticQuery drained %v, want [1 2]", got)
+	}
+}
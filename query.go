@@ -1,21 +1,9 @@
 package mtg
 
 import (
-	"encoding/json"
-	"io"
-	"net/http"
-	"net/url"
-	"regexp"
-	"strconv"
-	"strings"
+	"context"
 )
 
-const (
-	queryURL = "https://api.magicthegathering.io/v1/"
-)
-
-var linkRE = regexp.MustCompile(`<(.*)>; rel="(.*)"`)
-
 type cardColumn string
 
 var (
@@ -121,93 +109,60 @@ type Query interface {
 	PageS(pageNum int, pageSize int) (cards []*Card, totalCardCount int, err error)
 	// Fetches some random cards
 	Random(count int) ([]*Card, error)
+	// LegalIn filters the query down to cards that are legal in format.
+	LegalIn(format Format) Query
+	// BannedIn filters the query down to cards that are banned in format.
+	BannedIn(format Format) Query
+	// RestrictedIn filters the query down to cards that are restricted in format.
+	RestrictedIn(format Format) Query
+	// LegalInAll filters the query down to cards that are legal in every one of formats.
+	LegalInAll(formats ...Format) Query
+	// LegalInAny filters the query down to cards that are legal in at least one of formats.
+	LegalInAny(formats ...Format) Query
+	// Iter streams the cards matching the query one at a time, fetching
+	// pages in the background and honoring ctx cancellation between them.
+	Iter(ctx context.Context) *CardIter
+	// IterPages streams the cards matching the query one page at a time.
+	IterPages(ctx context.Context) *PageIter
 }
 
-// NewQuery creates a new Query to fetch cards.
-func NewQuery() Query {
-	return make(query)
+// Backend executes the requests a Query makes. Client implements Backend by
+// calling the magicthegathering.io API; BulkStore implements it by querying
+// a local, offline index instead, so the same Query code works either way.
+type Backend interface {
+	// FetchAll returns every card matching values, following pagination
+	// until it's exhausted.
+	FetchAll(ctx context.Context, values map[string]string) ([]*Card, error)
+	// FetchPage returns one page of cards matching values, along with the
+	// total number of cards that match across all pages.
+	FetchPage(ctx context.Context, values map[string]string, pageNum, pageSize int) (cards []*Card, totalCardCount int, err error)
+	// FetchRandom returns count cards matching values, chosen at random.
+	FetchRandom(ctx context.Context, values map[string]string, count int) ([]*Card, error)
+	// FetchCursor returns the next batch of cards matching values following
+	// cursor, along with the cursor to pass on the next call ("" once the
+	// result set is exhausted). The empty cursor starts from the beginning.
+	// Each Backend defines its own cursor format; callers must treat it as
+	// opaque. Client's cursor is the API's Link: rel="next" URL, so this
+	// follows the exact same pagination the API exposes.
+	FetchCursor(ctx context.Context, values map[string]string, cursor string) (cards []*Card, nextCursor string, err error)
 }
 
-type query map[string]string
-
-func fetchCards(url string) ([]*Card, http.Header, error) {
-	// resp is http.Response
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	// resp.Body is io.ReadCloser
-	bdy := resp.Body
-	defer bdy.Close()
-	if err := checkError(resp); err != nil {
-		return nil, nil, err
-	}
-
-	cards, err := decodeCards(bdy)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return cards, resp.Header, nil
+// NewQuery creates a new Query to fetch cards using the default Client.
+func NewQuery() Query {
+	return defaultClient.QueryWithContext(context.Background())
 }
 
-// decodeCards unmarshals resp body to cardResponse struct.
-func decodeCards(reader io.Reader) ([]*Card, error) {
-	asBytes, err := io.ReadAll(reader)
-	if err != nil {
-		return nil, err
-	}
-
-	var cardResp cardResponse
-	if err := json.Unmarshal(asBytes, &cardResp); err != nil {
-		return nil, err
-	}
-	/*
-		decoder := json.NewDecoder(reader)
-		err = decoder.Decode(&cardResp)
-		if err != nil {
-			return nil, err
-		}
-	*/
-	if cardResp.Card != nil {
-		return []*Card{cardResp.Card}, nil
-	}
-
-	return cardResp.Cards, nil
+// query is the default Query implementation. It is bound to the Backend and
+// context.Context it was created with, so All/Page/PageS/Random can rate
+// limit themselves and bail out early when the context is canceled.
+type query struct {
+	backend Backend
+	ctx     context.Context
+	values  map[string]string
 }
 
 func (q query) All() ([]*Card, error) {
-	var allCards []*Card
-
-	queryVals := make(url.Values)
-	for k, v := range q {
-		queryVals.Set(k, v)
-	}
-	nextURL := queryURL + "cards?" + queryVals.Encode()
-	for nextURL != "" {
-		cards, header, err := fetchCards(nextURL)
-		if err != nil {
-			return nil, err
-		}
-
-		// TODO: Investigate this line's impact on workflow.
-		nextURL = ""
-		if linkH, ok := header["Link"]; ok {
-			parts := strings.Split(linkH[0], ",")
-			for _, link := range parts {
-				match := linkRE.FindStringSubmatch(link)
-				if match != nil {
-					if match[2] == "next" {
-						nextURL = match[1]
-					}
-				}
-			}
-		}
-
-		allCards = append(allCards, cards...)
-	}
-	return allCards, nil
+	return q.backend.FetchAll(q.ctx, q.values)
 }
 
 func (q query) Page(pageNum int) ([]*Card, int, error) {
@@ -215,64 +170,30 @@ func (q query) Page(pageNum int) ([]*Card, int, error) {
 }
 
 func (q query) PageS(pageNum int, pageSize int) ([]*Card, int, error) {
-	var cards []*Card
-	totalCardCount := 0
-
-	queryVals := make(url.Values)
-	for k, v := range q {
-		queryVals.Set(k, v)
-	}
-
-	queryVals.Set("page", strconv.Itoa(pageNum))
-	queryVals.Set("pageSize", strconv.Itoa(pageSize))
-
-	url := queryURL + "cards?" + queryVals.Encode()
-	cards, header, err := fetchCards(url)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	totalCardCount = len(cards)
-	if totals, ok := header["Total-Count"]; ok && len(totals) > 0 {
-		if totalCardCount, err = strconv.Atoi(totals[0]); err != nil {
-			return nil, 0, err
-		}
-	}
-
-	return cards, totalCardCount, nil
+	return q.backend.FetchPage(q.ctx, q.values, pageNum, pageSize)
 }
 
 // Random cards by page size.
 func (q query) Random(count int) ([]*Card, error) {
-	queryVals := make(url.Values)
-	for k, v := range q {
-		queryVals.Set(k, v)
-	}
-
-	queryVals.Set("random", "true")
-	queryVals.Set("pageSize", strconv.Itoa(count))
-
-	url := queryURL + "cards?" + queryVals.Encode()
-	cards, _, err := fetchCards(url)
-	return cards, err
+	return q.backend.FetchRandom(q.ctx, q.values, count)
 }
 
-// Copy builds a new map using existing parameters.
+// Copy builds a new query using existing parameters, backend and context.
 func (q query) Copy() Query {
-	r := make(query)
-	for k, v := range q {
+	r := make(map[string]string, len(q.values))
+	for k, v := range q.values {
 		r[k] = v
 	}
-	return r
+	return query{backend: q.backend, ctx: q.ctx, values: r}
 }
 
 // Where adds parameters to a map used in url.Values.
 func (q query) Where(column cardColumn, qry string) Query {
-	q[string(column)] = qry
+	q.values[string(column)] = qry
 	return q
 }
 
 func (q query) OrderBy(column cardColumn) Query {
-	q["orderBy"] = string(column)
+	q.values["orderBy"] = string(column)
 	return q
 }
@@ -0,0 +1,287 @@
+package mtg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// searchField describes how a single operator in the mini-language maps onto
+// a Where call (or, for formats, a Where+Where pair).
+type searchField struct {
+	column cardColumn
+	// numeric marks fields that accept the >, >=, <, <=, = comparators.
+	numeric bool
+}
+
+// searchFields maps every operator keyword (and its short alias) recognized
+// by ParseSearch to the column it filters.
+var searchFields = map[string]searchField{
+	"c":        {column: CardColors},
+	"color":    {column: CardColors},
+	"id":       {column: CardColorIdentity},
+	"identity": {column: CardColorIdentity},
+	"t":        {column: CardType},
+	"type":     {column: CardType},
+	"o":        {column: CardText},
+	"oracle":   {column: CardText},
+	"pow":      {column: CardPower, numeric: true},
+	"tou":      {column: CardToughness, numeric: true},
+	"cmc":      {column: CardCMC, numeric: true},
+	"r":        {column: CardRarity},
+	"rarity":   {column: CardRarity},
+	"s":        {column: CardSet},
+	"set":      {column: CardSet},
+	"f":        {column: CardGameFormat},
+	"format":   {column: CardGameFormat},
+	"name":     {column: CardName},
+}
+
+// comparatorSuffix maps the mini-language's numeric comparators onto the
+// prefix the API expects in front of a numeric query value, e.g. "cmc<=3"
+// becomes cmc=lte3. Plain "=" needs no prefix.
+var comparatorSuffix = map[string]string{
+	">=": "gte",
+	"<=": "lte",
+	">":  "gt",
+	"<":  "lt",
+	"=":  "",
+}
+
+// SearchParseError is returned by ParseSearch when expr contains a token
+// ParseSearch doesn't understand. Pos is the byte offset of the offending
+// token within expr, so a front-end can underline it.
+type SearchParseError struct {
+	Expr string
+	Pos  int
+	Msg  string
+}
+
+func (e *SearchParseError) Error() string {
+	return fmt.Sprintf("mtg: invalid search expression at position %d: %s", e.Pos, e.Msg)
+}
+
+// ParseSearch parses a Scryfall-inspired search expression into a Query.
+//
+// Supported operators: c/color, id/identity, t/type, o/oracle, pow, tou,
+// cmc, r/rarity, s/set, f/format and name. Operators may be negated with a
+// leading "-" (e.g. -t:legendary), values may be quoted phrases
+// (name:"goblin"), and pow/tou/cmc accept the comparators >, >=, <, <= and =
+// directly after the operator with no ":" (e.g. pow>=4). c/color and
+// id/identity values are color letters (e.g. c:rg) translated to the API's
+// color codes ("R,G"); f/format values are matched against the Format enum
+// case-insensitively (e.g. f:modern -> "Modern") and additionally restrict
+// CardLegality to Legal.
+func ParseSearch(expr string) (Query, error) {
+	q := NewQuery()
+	tokens, err := tokenizeSearch(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tok := range tokens {
+		if err := applySearchToken(q, expr, tok); err != nil {
+			return nil, err
+		}
+	}
+
+	return q, nil
+}
+
+// searchToken is a single "operator:value" (or bare keyword) clause together
+// with its negation flag and position within the original expression.
+type searchToken struct {
+	negate bool
+	raw    string
+	pos    int
+}
+
+// tokenizeSearch splits expr on whitespace, keeping quoted phrases intact.
+func tokenizeSearch(expr string) ([]searchToken, error) {
+	var tokens []searchToken
+
+	i := 0
+	n := len(expr)
+	for i < n {
+		for i < n && expr[i] == ' ' {
+			i++
+		}
+		if i >= n {
+			break
+		}
+
+		start := i
+		negate := false
+		if expr[i] == '-' {
+			negate = true
+			i++
+		}
+
+		tokStart := i
+		for i < n && expr[i] != ' ' {
+			if expr[i] == '"' {
+				i++
+				for i < n && expr[i] != '"' {
+					i++
+				}
+				if i >= n {
+					return nil, &SearchParseError{Expr: expr, Pos: tokStart, Msg: "unterminated quoted phrase"}
+				}
+			}
+			i++
+		}
+
+		tokens = append(tokens, searchToken{negate: negate, raw: expr[tokStart:i], pos: start})
+	}
+
+	return tokens, nil
+}
+
+// applySearchToken resolves one token to its column/value pair and calls
+// Where (or Where twice, for formats) on q.
+func applySearchToken(q Query, expr string, tok searchToken) error {
+	op, sep, value, ok := splitSearchClause(tok.raw)
+	if !ok {
+		return &SearchParseError{Expr: expr, Pos: tok.pos, Msg: fmt.Sprintf("missing ':' in clause %q", tok.raw)}
+	}
+
+	field, ok := searchFields[strings.ToLower(op)]
+	if !ok {
+		return &SearchParseError{Expr: expr, Pos: tok.pos, Msg: fmt.Sprintf("unknown search operator %q", op)}
+	}
+	if sep != ':' && !field.numeric {
+		return &SearchParseError{Expr: expr, Pos: tok.pos, Msg: fmt.Sprintf("%q requires ':' before its value", op)}
+	}
+
+	value = strings.Trim(value, `"`)
+	if value == "" {
+		return &SearchParseError{Expr: expr, Pos: tok.pos, Msg: fmt.Sprintf("%q requires a value", op)}
+	}
+
+	resolved, err := resolveSearchValue(expr, tok, field, value)
+	if err != nil {
+		return err
+	}
+	resolved, err = translateSearchValue(expr, tok, field, resolved)
+	if err != nil {
+		return err
+	}
+	if tok.negate {
+		resolved = "-" + resolved
+	}
+
+	q.Where(field.column, resolved)
+	if field.column == CardGameFormat {
+		q.Where(CardLegality, "Legal")
+	}
+
+	return nil
+}
+
+// splitSearchClause splits raw into its operator and value. Non-numeric
+// operators must be followed by ":", e.g. "t:creature". Numeric operators
+// may instead be followed directly by a comparator, e.g. "pow>=4" — sep
+// reports which separator was found so the caller can reject a comparator
+// on a non-numeric field.
+func splitSearchClause(raw string) (op string, sep byte, value string, ok bool) {
+	idx := strings.IndexAny(raw, ":><=")
+	if idx < 0 {
+		return "", 0, "", false
+	}
+
+	op = raw[:idx]
+	sep = raw[idx]
+	value = raw[idx:]
+	if sep == ':' {
+		value = value[1:]
+	}
+	return op, sep, value, true
+}
+
+// resolveSearchValue handles the numeric comparator prefix on pow/tou/cmc
+// clauses, e.g. "pow>=4" -> "gte4". A value with no comparator prefix passes
+// through unchanged; a comparator on a non-numeric field is an error.
+func resolveSearchValue(expr string, tok searchToken, field searchField, value string) (string, error) {
+	cmp, rest := splitComparator(value)
+	if cmp == "" {
+		return value, nil
+	}
+
+	if !field.numeric {
+		return "", &SearchParseError{Expr: expr, Pos: tok.pos, Msg: fmt.Sprintf("comparator %q is not supported for this field", cmp)}
+	}
+	if _, err := strconv.Atoi(rest); err != nil {
+		return "", &SearchParseError{Expr: expr, Pos: tok.pos, Msg: fmt.Sprintf("%q is not a number", rest)}
+	}
+
+	suffix, ok := comparatorSuffix[cmp]
+	if !ok {
+		return "", &SearchParseError{Expr: expr, Pos: tok.pos, Msg: fmt.Sprintf("unsupported comparator %q", cmp)}
+	}
+	return suffix + rest, nil
+}
+
+// splitComparator splits a leading >=, <=, >, < or = off value. It returns
+// an empty comparator when value has none of these prefixes.
+func splitComparator(value string) (cmp string, rest string) {
+	for _, c := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(value, c) {
+			return c, value[len(c):]
+		}
+	}
+	return "", value
+}
+
+// colorCodes maps the single-letter color shorthand the mini-language
+// accepts (as in "c:rg") to the API's color code.
+var colorCodes = map[rune]string{
+	'w': "W",
+	'u': "U",
+	'b': "B",
+	'r': "R",
+	'g': "G",
+}
+
+// translateSearchValue rewrites a clause's value into the form the API
+// expects, for fields where the mini-language's spelling differs from the
+// API's: color letters (c:rg -> "R,G") and format names (f:modern ->
+// "Modern"). Other fields pass value through unchanged.
+func translateSearchValue(expr string, tok searchToken, field searchField, value string) (string, error) {
+	switch field.column {
+	case CardColors, CardColorIdentity:
+		return translateColorValue(expr, tok, value)
+	case CardGameFormat:
+		return translateFormatValue(expr, tok, value)
+	}
+	return value, nil
+}
+
+// translateColorValue turns a run of color letters (e.g. "rg", optionally
+// comma-separated) into the comma-separated color codes the API expects
+// (e.g. "R,G").
+func translateColorValue(expr string, tok searchToken, value string) (string, error) {
+	var codes []string
+	for _, r := range value {
+		if r == ',' {
+			continue
+		}
+		code, ok := colorCodes[unicode.ToLower(r)]
+		if !ok {
+			return "", &SearchParseError{Expr: expr, Pos: tok.pos, Msg: fmt.Sprintf("unknown color %q", string(r))}
+		}
+		codes = append(codes, code)
+	}
+	return strings.Join(codes, ","), nil
+}
+
+// translateFormatValue resolves value against the Format enum
+// case-insensitively, e.g. "modern" -> "Modern", so the API sees the
+// capitalization it actually expects.
+func translateFormatValue(expr string, tok searchToken, value string) (string, error) {
+	format, ok := formatByName(value)
+	if !ok {
+		return "", &SearchParseError{Expr: expr, Pos: tok.pos, Msg: fmt.Sprintf("unknown format %q", value)}
+	}
+	return string(format), nil
+}
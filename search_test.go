@@ -0,0 +1,137 @@
+package mtg
+
+import "testing"
+
+func parseSearchValues(t *testing.T, expr string) map[string]string {
+	t.Helper()
+
+	q, err := ParseSearch(expr)
+	if err != nil {
+		t.Fatalf("ParseSearch(%q): %v", expr, err)
+	}
+
+	qq, ok := q.(query)
+	if !ok {
+		t.Fatalf("ParseSearch(%q) returned %T, want query", expr, q)
+	}
+	return qq.values
+}
+
+func TestParseSearchExample(t *testing.T) {
+	// The expression documented in the backlog request this feature shipped
+	// under. t:creature and -t:legendary both target CardType, so (per
+	// Where's normal last-write-wins semantics) the negated clause is what
+	// survives.
+	values := parseSearchValues(t, `c:rg t:creature pow>=4 cmc<=3 f:modern -t:legendary name:"goblin"`)
+
+	want := map[string]string{
+		string(CardColors):     "R,G",
+		string(CardType):       "-legendary",
+		string(CardPower):      "gte4",
+		string(CardCMC):        "lte3",
+		string(CardGameFormat): "Modern",
+		string(CardLegality):   "Legal",
+		string(CardName):       "goblin",
+	}
+	for column, wantValue := range want {
+		if got := values[column]; got != wantValue {
+			t.Errorf("values[%q] = %q, want %q", column, got, wantValue)
+		}
+	}
+}
+
+func TestParseSearchComparatorsWithoutColon(t *testing.T) {
+	values := parseSearchValues(t, "pow>=4 cmc<=3 tou>2")
+
+	want := map[string]string{
+		string(CardPower):     "gte4",
+		string(CardCMC):       "lte3",
+		string(CardToughness): "gt2",
+	}
+	for column, wantValue := range want {
+		if got := values[column]; got != wantValue {
+			t.Errorf("values[%q] = %q, want %q", column, got, wantValue)
+		}
+	}
+}
+
+func TestParseSearchNegationAndQuoting(t *testing.T) {
+	values := parseSearchValues(t, `-t:legendary name:"goblin soldier"`)
+
+	if got := values[string(CardType)]; got != "-legendary" {
+		t.Errorf("CardType = %q, want %q", got, "-legendary")
+	}
+	if got := values[string(CardName)]; got != "goblin soldier" {
+		t.Errorf("CardName = %q, want %q", got, "goblin soldier")
+	}
+}
+
+func TestParseSearchColorTranslation(t *testing.T) {
+	values := parseSearchValues(t, "c:rg id:u")
+
+	if got := values[string(CardColors)]; got != "R,G" {
+		t.Errorf("CardColors = %q, want %q", got, "R,G")
+	}
+	if got := values[string(CardColorIdentity)]; got != "U" {
+		t.Errorf("CardColorIdentity = %q, want %q", got, "U")
+	}
+}
+
+func TestParseSearchFormatTranslation(t *testing.T) {
+	values := parseSearchValues(t, "f:modern")
+
+	if got := values[string(CardGameFormat)]; got != "Modern" {
+		t.Errorf("CardGameFormat = %q, want %q", got, "Modern")
+	}
+	if got := values[string(CardLegality)]; got != "Legal" {
+		t.Errorf("CardLegality = %q, want %q", got, "Legal")
+	}
+}
+
+func TestParseSearchUnknownOperator(t *testing.T) {
+	_, err := ParseSearch("xyz:foo")
+	if err == nil {
+		t.Fatal("expected error for unknown operator, got nil")
+	}
+	if _, ok := err.(*SearchParseError); !ok {
+		t.Fatalf("got error of type %T, want *SearchParseError", err)
+	}
+}
+
+func TestParseSearchMissingColon(t *testing.T) {
+	_, err := ParseSearch("t>creature")
+	if err == nil {
+		t.Fatal("expected error for comparator on a non-numeric field, got nil")
+	}
+	if _, ok := err.(*SearchParseError); !ok {
+		t.Fatalf("got error of type %T, want *SearchParseError", err)
+	}
+}
+
+func TestParseSearchUnknownColor(t *testing.T) {
+	_, err := ParseSearch("c:x")
+	if err == nil {
+		t.Fatal("expected error for unknown color code, got nil")
+	}
+}
+
+func TestParseSearchUnknownFormat(t *testing.T) {
+	_, err := ParseSearch("f:notaformat")
+	if err == nil {
+		t.Fatal("expected error for unknown format, got nil")
+	}
+}
+
+func TestParseSearchBadComparatorNumber(t *testing.T) {
+	_, err := ParseSearch("pow>=abc")
+	if err == nil {
+		t.Fatal("expected error for non-numeric comparator value, got nil")
+	}
+}
+
+func TestParseSearchUnterminatedQuote(t *testing.T) {
+	_, err := ParseSearch(`name:"goblin`)
+	if err == nil {
+		t.Fatal("expected error for unterminated quoted phrase, got nil")
+	}
+}